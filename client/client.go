@@ -0,0 +1,178 @@
+// Package client fetches the published GPU catalog over HTTP, so consumers
+// such as provider-services and the inventory operator can use the typed
+// structs in the schema package instead of re-declaring them.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/akash-network/provider-configs/schema"
+)
+
+// DefaultURL is the published GPU catalog on the main branch.
+const DefaultURL = "https://raw.githubusercontent.com/akash-network/provider-configs/main/devices/pcie/gpus.json"
+
+// Client fetches and caches the GPU catalog. Its fields must not be changed
+// once it is in use. A Client is safe for concurrent use.
+type Client struct {
+	// URL is the catalog location.
+	URL string
+
+	// HTTPClient sends the requests.
+	HTTPClient *http.Client
+
+	// Retries is the number of attempts made after the first when a
+	// request fails with a network error, 429, or a 5xx status.
+	Retries int
+
+	// Backoff is the delay before the first retry. It doubles on each
+	// further retry, up to MaxBackoff, and a random jitter of up to the
+	// same amount is added. A longer Retry-After header is honored, up to
+	// MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	mu   sync.Mutex
+	etag string
+	gpus schema.GPUs
+}
+
+// New returns a Client for DefaultURL with three retries.
+func New() *Client {
+	return &Client{
+		URL:        DefaultURL,
+		HTTPClient: http.DefaultClient,
+		Retries:    3,
+		Backoff:    500 * time.Millisecond,
+		MaxBackoff: 10 * time.Second,
+	}
+}
+
+// GPUs returns the current catalog. Each call makes a conditional request
+// with the ETag of the last response, and a 304 Not Modified returns the
+// cached copy. The result is shared between calls and must not be modified.
+//
+// Fields the schema package does not know are ignored rather than rejected,
+// so clients keep working when the catalog gains a field.
+func (c *Client) GPUs(ctx context.Context) (schema.GPUs, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retryAfter time.Duration
+		retryAfter, err = c.fetch(ctx)
+		if err == nil {
+			return c.gpus, nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) || attempt >= c.Retries {
+			break
+		}
+
+		timer := time.NewTimer(max(c.delay(attempt), min(retryAfter, c.MaxBackoff)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return nil, err
+}
+
+// DeviceByPCIID looks up a device in the current catalog by vendor and device
+// ID, in any case.
+func (c *Client) DeviceByPCIID(ctx context.Context, vendorID, deviceID string) (schema.PCIeDevice, bool, error) {
+	g, err := c.GPUs(ctx)
+	if err != nil {
+		return schema.PCIeDevice{}, false, err
+	}
+	d, ok := g.Device(vendorID, deviceID)
+	return d, ok, nil
+}
+
+// permanentError is a failure that retrying will not fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// fetch makes one request and updates the cache. On a retryable status it
+// also returns the delay asked for by a Retry-After header.
+func (c *Client) fetch(ctx context.Context) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return 0, &permanentError{err}
+	}
+	if c.gpus != nil && c.etag != "" {
+		req.Header.Set("If-None-Match", c.etag)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, &permanentError{err}
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && c.gpus != nil:
+		return 0, nil
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return retryAfter(resp.Header), fmt.Errorf("GET %s: %s", c.URL, resp.Status)
+	default:
+		return 0, &permanentError{fmt.Errorf("GET %s: %s", c.URL, resp.Status)}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	var g schema.GPUs
+	if err := json.Unmarshal(data, &g); err != nil {
+		return 0, &permanentError{fmt.Errorf("GET %s: %w", c.URL, err)}
+	}
+
+	c.gpus, c.etag = g, resp.Header.Get("ETag")
+	return 0, nil
+}
+
+// delay returns the backoff before retry number attempt+1.
+func (c *Client) delay(attempt int) time.Duration {
+	d := c.Backoff
+	for i := 0; i < attempt && d < c.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, c.MaxBackoff)
+	if d <= 0 {
+		return 0
+	}
+	return d + rand.N(d)
+}
+
+// retryAfter parses a Retry-After header given in seconds. HTTP dates are
+// not supported and give zero.
+func retryAfter(h http.Header) time.Duration {
+	s, err := strconv.Atoi(h.Get("Retry-After"))
+	if err != nil || s < 0 {
+		return 0
+	}
+	return time.Duration(s) * time.Second
+}
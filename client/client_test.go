@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const catalog = `{"10de":{"name":"nvidia","devices":{"20b5":{"name":"a100","interface":"PCIe","memory_size":"80Gi","new_field":true}}}}`
+
+func newTestClient(url string) *Client {
+	c := New()
+	c.URL = url
+	c.Backoff = time.Millisecond
+	c.MaxBackoff = 2 * time.Millisecond
+	return c
+}
+
+func TestETag(t *testing.T) {
+	var requests, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(catalog))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+	for i := 0; i < 3; i++ {
+		d, ok, err := c.DeviceByPCIID(context.Background(), "10DE", "20B5")
+		if err != nil {
+			t.Fatalf("DeviceByPCIID() error = %v", err)
+		}
+		if !ok || d.Name != "a100" || d.MemorySize != "80Gi" {
+			t.Errorf("DeviceByPCIID(10DE, 20B5) = %+v, %v", d, ok)
+		}
+	}
+	if requests.Load() != 3 || notModified.Load() != 2 {
+		t.Errorf("got %d requests with %d not modified, want 3 with 2", requests.Load(), notModified.Load())
+	}
+
+	if _, ok, err := c.DeviceByPCIID(context.Background(), "10de", "ffff"); err != nil || ok {
+		t.Errorf("DeviceByPCIID(10de, ffff) = %v, %v, want not found", ok, err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch requests.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(catalog))
+		}
+	}))
+	defer srv.Close()
+
+	if _, err := newTestClient(srv.URL).GPUs(context.Background()); err != nil {
+		t.Fatalf("GPUs() error = %v", err)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("got %d requests, want 3", requests.Load())
+	}
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+	_, err := c.GPUs(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "502 Bad Gateway") {
+		t.Errorf("GPUs() error = %v, want 502", err)
+	}
+	if int(requests.Load()) != c.Retries+1 {
+		t.Errorf("got %d requests, want %d", requests.Load(), c.Retries+1)
+	}
+}
+
+func TestPermanentErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+	}{
+		{"not found", func(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) }, "404 Not Found"},
+		{"not JSON", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("<html>")) }, "invalid character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				tt.handler(w, r)
+			}))
+			defer srv.Close()
+
+			_, err := newTestClient(srv.URL).GPUs(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("GPUs() error = %v, want %q", err, tt.want)
+			}
+			if requests.Load() != 1 {
+				t.Errorf("got %d requests, want 1", requests.Load())
+			}
+		})
+	}
+}
+
+func TestContextCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+	c.Backoff, c.MaxBackoff = time.Hour, time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.GPUs(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GPUs() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestPublishedCatalog(t *testing.T) {
+	data, err := os.ReadFile("../devices/pcie/gpus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	g, err := newTestClient(srv.URL).GPUs(context.Background())
+	if err != nil {
+		t.Fatalf("GPUs() error = %v", err)
+	}
+	if _, ok := g.Device("1002", "738c"); !ok {
+		t.Error("Device(1002, 738c) missing from the catalog")
+	}
+}
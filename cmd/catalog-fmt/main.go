@@ -0,0 +1,136 @@
+// Command catalog-fmt rewrites GPU catalog files in canonical form: vendor
+// and device keys sorted and in lowercase hex, fields in schema order, memory
+// sizes in canonical units (e.g. "16384 MiB" as "16Gi"), and two-space
+// indentation.
+//
+// Usage:
+//
+//	catalog-fmt [-l] [-w] [path ...]
+//
+// With no paths, devices/pcie/gpus.json is formatted. By default the result
+// is written to standard output. The flags are:
+//
+//	-l	list files whose formatting differs from catalog-fmt's
+//	-w	write the result back to the file instead of standard output
+//
+// Files with unknown fields or duplicate keys are refused rather than
+// formatted, since either would be lost. The formatted result is checked
+// with the validate package, and a result that fails is reported and never
+// written. The exit status is 1 if any file was refused or failed validation
+// and 2 if a file could not be read or written.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/akash-network/provider-configs/schema"
+)
+
+var (
+	list  = flag.Bool("l", false, "list files whose formatting differs from catalog-fmt's")
+	write = flag.Bool("w", false, "write result to the file instead of standard output")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: catalog-fmt [-l] [-w] [path ...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{"devices/pcie/gpus.json"}
+	}
+
+	status := 0
+	for _, path := range paths {
+		if err := process(path); err != nil {
+			fmt.Fprintf(os.Stderr, "catalog-fmt: %v\n", err)
+
+			var invalid *invalidError
+			if errors.As(err, &invalid) {
+				status = max(status, 1)
+			} else {
+				status = 2
+			}
+		}
+	}
+	os.Exit(status)
+}
+
+func process(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	res, err := format(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if *list && !bytes.Equal(src, res) {
+		fmt.Println(path)
+	}
+	if *write {
+		if bytes.Equal(src, res) {
+			return nil
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, res, info.Mode().Perm())
+	}
+	if !*list {
+		_, err = os.Stdout.Write(res)
+	}
+
+	return err
+}
+
+// invalidError reports a catalog that cannot be formatted, or whose formatted
+// form does not pass validation.
+type invalidError struct {
+	reason string
+	errs   []error
+}
+
+func (e *invalidError) Error() string {
+	msg := e.reason + ":"
+	for _, err := range e.errs {
+		msg += "\n\t" + strings.ReplaceAll(err.Error(), "\n", "\n\t")
+	}
+	return msg
+}
+
+// format returns the canonical form of a GPU catalog.
+func format(src []byte) ([]byte, error) {
+	g, err := schema.ParseGPUs(src)
+	if err != nil {
+		return nil, &invalidError{reason: "cannot format catalog", errs: []error{err}}
+	}
+	if g, err = g.Canonicalize(); err != nil {
+		return nil, &invalidError{reason: "cannot format catalog", errs: []error{err}}
+	}
+
+	problems, err := g.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) != 0 {
+		invalid := &invalidError{reason: "formatted catalog is not valid"}
+		for _, p := range problems {
+			invalid.errs = append(invalid.errs, p)
+		}
+		return nil, invalid
+	}
+
+	return g.Marshal()
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akash-network/provider-configs/schema"
+)
+
+func TestFormatRoundTrip(t *testing.T) {
+	src, err := os.ReadFile("../../devices/pcie/gpus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := format(src)
+	if err != nil {
+		t.Fatalf("format() error = %v", err)
+	}
+
+	again, err := format(out)
+	if err != nil {
+		t.Fatalf("format(format()) error = %v", err)
+	}
+	if string(again) != string(out) {
+		t.Error("format() is not idempotent")
+	}
+
+	before, err := schema.ParseGPUs(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := schema.ParseGPUs(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(before, after) {
+		t.Error("format() changed the catalog contents")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	src := `{"1002": {"devices": {"738C": {"memory_size": "32 GiB", "interface": "PCIe", "name": "mi100"}}, "name": "amd"},
+"10DE": {"name": "nvidia", "devices": {
+	"20b5": {"name": "a100", "interface": "PCIe", "memory_size": "81920MiB"},
+	"1EB8": {"memory_size": "16GB", "name": "t4", "interface": "PCIe"}}}}`
+
+	want := `{
+  "1002": {
+    "name": "amd",
+    "devices": {
+      "738c": {
+        "name": "mi100",
+        "interface": "PCIe",
+        "memory_size": "32Gi"
+      }
+    }
+  },
+  "10de": {
+    "name": "nvidia",
+    "devices": {
+      "1eb8": {
+        "name": "t4",
+        "interface": "PCIe",
+        "memory_size": "16Gi"
+      },
+      "20b5": {
+        "name": "a100",
+        "interface": "PCIe",
+        "memory_size": "80Gi"
+      }
+    }
+  }
+}
+`
+
+	got, err := format([]byte(src))
+	if err != nil {
+		t.Fatalf("format() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("format() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatRefuses(t *testing.T) {
+	device := `"name":"a100","interface":"PCIe","memory_size":"80Gi"`
+
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"duplicate key", `{"10de":{"name":"nvidia","devices":{"20b5":{` + device + `},"20b5":{` + device + `}}}}`,
+			`/10de/devices/20b5: duplicate key "20b5"`},
+		{"unknown field", `{"10de":{"name":"nvidia","devices":{"20b5":{` + device + `,"memroy_size":"80Gi"}}}}`,
+			`unknown field "memroy_size"`},
+		{"IDs differ only in case", `{"10de":{"name":"nvidia","devices":{"20b5":{` + device + `},"20B5":{` + device + `}}}}`,
+			"duplicate device ID 20b5"},
+		{"unparseable memory", `{"10de":{"name":"nvidia","devices":{"20b5":{"name":"a100","interface":"PCIe","memory_size":"lots"}}}}`,
+			`invalid memory size "lots"`},
+		{"invalid result", `{"10de":{"name":"amd","devices":{"20b5":{"name":"a100","interface":"pcie","memory_size":"80Gi"}}}}`,
+			"formatted catalog is not valid:\n\t/10de/devices/20b5/interface: \"pcie\" is not one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := format([]byte(tt.src))
+
+			var invalid *invalidError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("format() error = %v, want *invalidError", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("format() error = %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Command catalog-validate checks device catalog files before they are
+// submitted for review.
+//
+// Usage:
+//
+//	catalog-validate [path ...]
+//
+// Each path may be a catalog file or a directory, which is searched
+// recursively; with no arguments the devices directory is checked. Catalogs
+// are validated against the <name>.schema.json file next to them and checked
+// for duplicate keys. Schema files named on the command line are rejected;
+// inside a directory they are skipped. Problems are printed one per line and
+// every path is checked even if an earlier one fails. The exit status is 2 if
+// any path could not be checked at all, 1 if problems were found, and 0
+// otherwise.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/akash-network/provider-configs/validate"
+)
+
+func main() {
+	paths := os.Args[1:]
+	if len(paths) == 0 {
+		paths = []string{"devices"}
+	}
+
+	status := 0
+	problems := 0
+	for _, path := range paths {
+		errs, err := check(path)
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		problems += len(errs)
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "catalog-validate: %v\n", err)
+			status = 2
+		} else if len(errs) != 0 && status == 0 {
+			status = 1
+		}
+	}
+
+	if problems != 0 {
+		fmt.Fprintf(os.Stderr, "catalog-validate: %d problem(s) found\n", problems)
+	}
+	os.Exit(status)
+}
+
+func check(path string) ([]validate.Error, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return validate.Tree(path)
+	}
+	if strings.HasSuffix(path, ".schema.json") {
+		return nil, fmt.Errorf("%s: is a schema, not a catalog", path)
+	}
+	return validate.File(path)
+}
@@ -0,0 +1,215 @@
+// Command gpu-report runs on a provider node and reports which of its GPUs
+// the GPU catalog knows, and so which the provider will advertise.
+//
+// Usage:
+//
+//	gpu-report [-lspci file] [-catalog file] [-url url] [-issue]
+//
+// Devices are read from the output of lspci -nn, which is run unless -lspci
+// names a file holding its output ("-" for standard input). Display
+// controllers (class 03xx) and processing accelerators (class 12xx) are
+// matched against the catalog published on the main branch, or against a
+// local copy given with -catalog. The flags are:
+//
+//	-lspci file	read lspci -nn output from file instead of running lspci
+//	-catalog file	match against a local gpus.json instead of fetching it
+//	-url url	fetch the catalog from url
+//	-issue		print a pre-filled GitHub issue URL for each unknown device
+//
+// Unknown devices are not submitted anywhere; open the printed issue, or a
+// pull request against devices/pcie/gpus.json, to get them added. The exit
+// status is 2 if the devices or the catalog could not be read and 0
+// otherwise.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/akash-network/provider-configs/client"
+	"github.com/akash-network/provider-configs/schema"
+)
+
+var (
+	lspciFile   = flag.String("lspci", "", "read lspci -nn output from `file` instead of running lspci")
+	catalogFile = flag.String("catalog", "", "match against a local gpus.json `file` instead of fetching it")
+	catalogURL  = flag.String("url", client.DefaultURL, "fetch the catalog from `url`")
+	issue       = flag.Bool("issue", false, "print a pre-filled GitHub issue URL for each unknown device")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: gpu-report [-lspci file] [-catalog file] [-url url] [-issue]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	devices, err := readDevices()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gpu-report: %v\n", err)
+		os.Exit(2)
+	}
+
+	gpus, err := readCatalog()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gpu-report: %v\n", err)
+		os.Exit(2)
+	}
+
+	report(os.Stdout, devices, gpus, *issue)
+}
+
+func readDevices() ([]pciDevice, error) {
+	var data []byte
+	var err error
+	switch *lspciFile {
+	case "":
+		data, err = exec.Command("lspci", "-nn").Output()
+		if err != nil {
+			return nil, fmt.Errorf("lspci -nn: %w", err)
+		}
+	case "-":
+		data, err = io.ReadAll(os.Stdin)
+	default:
+		data, err = os.ReadFile(*lspciFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLSPCI(bytes.NewReader(data))
+}
+
+func readCatalog() (schema.GPUs, error) {
+	if *catalogFile != "" {
+		data, err := os.ReadFile(*catalogFile)
+		if err != nil {
+			return nil, err
+		}
+		g, err := schema.ParseGPUs(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", *catalogFile, err)
+		}
+		return g, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	c := client.New()
+	c.URL = *catalogURL
+	return c.GPUs(ctx)
+}
+
+// pciDevice is one line of lspci -nn output.
+type pciDevice struct {
+	slot     string
+	class    string
+	desc     string
+	vendorID string
+	deviceID string
+	line     string
+}
+
+// lspciLine matches a line of lspci -nn output, e.g.
+//
+//	01:00.0 3D controller [0302]: NVIDIA Corporation GA100 [A100 PCIe 80GB] [10de:20b5] (rev a1)
+//
+// The description is greedy so that brackets in device names are kept and the
+// last [vendor:device] pair is taken as the IDs.
+var lspciLine = regexp.MustCompile(`^(\S+) [^\[]+ \[([0-9a-f]{4})\]: (.*) \[([0-9a-f]{4}):([0-9a-f]{4})\]`)
+
+// parseLSPCI returns the display controllers and processing accelerators in
+// lspci -nn output. Other devices are skipped, as are lines that are not in
+// lspci -nn format, such as those added by -v.
+func parseLSPCI(r io.Reader) ([]pciDevice, error) {
+	var res []pciDevice
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		m := lspciLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		class := m[2]
+		if !strings.HasPrefix(class, "03") && !strings.HasPrefix(class, "12") {
+			continue
+		}
+		res = append(res, pciDevice{
+			slot:     m[1],
+			class:    class,
+			desc:     m[3],
+			vendorID: m[4],
+			deviceID: m[5],
+			line:     line,
+		})
+	}
+
+	return res, sc.Err()
+}
+
+// report prints the devices the catalog knows, then those it does not.
+func report(w io.Writer, devices []pciDevice, gpus schema.GPUs, issue bool) {
+	var known, unknown []string
+	for _, d := range devices {
+		id := d.vendorID + ":" + d.deviceID
+
+		entry, ok := gpus.Device(d.vendorID, d.deviceID)
+		if !ok {
+			line := fmt.Sprintf("\t%s\t%s\t%s", d.slot, id, d.desc)
+			if _, ok := gpus[d.vendorID]; !ok {
+				line += " (vendor not in catalog)"
+			}
+			if issue {
+				line += "\n\t\t" + issueURL(d)
+			}
+			unknown = append(unknown, line)
+			continue
+		}
+
+		line := fmt.Sprintf("\t%s\t%s\t%s %s %s %s", d.slot, id, gpus[d.vendorID].Name, entry.Name, entry.MemorySize, entry.Interface)
+		if entry.Deprecated {
+			line += " (deprecated)"
+		}
+		known = append(known, line)
+	}
+
+	if len(devices) == 0 {
+		fmt.Fprintln(w, "no display controllers or processing accelerators found")
+		return
+	}
+	if len(known) != 0 {
+		fmt.Fprintln(w, "advertisable:")
+		fmt.Fprintln(w, strings.Join(known, "\n"))
+	}
+	if len(unknown) != 0 {
+		fmt.Fprintln(w, "not in catalog:")
+		fmt.Fprintln(w, strings.Join(unknown, "\n"))
+	}
+}
+
+// issueURL returns a link that opens a GitHub issue asking for d to be added
+// to the catalog.
+func issueURL(d pciDevice) string {
+	q := url.Values{
+		"title": {fmt.Sprintf("Add GPU %s:%s", d.vendorID, d.deviceID)},
+		"body": {fmt.Sprintf("gpu-report found a device that is not in devices/pcie/gpus.json.\n\n"+
+			"lspci -nn:\n\n```\n%s\n```\n", d.line)},
+	}
+	return "https://github.com/akash-network/provider-configs/issues/new?" + q.Encode()
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akash-network/provider-configs/schema"
+)
+
+const lspciOutput = `00:00.0 Host bridge [0600]: Intel Corporation Device [8086:09a2] (rev 04)
+02:00.0 VGA compatible controller [0300]: ASPEED Technology, Inc. ASPEED Graphics Family [1a03:2000] (rev 41)
+0000:17:00.0 3D controller [0302]: NVIDIA Corporation GA100 [A100 PCIe 80GB] [10de:20b5] (rev a1)
+	Subsystem: NVIDIA Corporation Device [10de:1533]
+65:00.0 VGA compatible controller [0300]: NVIDIA Corporation AD102 [GeForce RTX 4090] [10de:2684] (rev a1) (prog-if 00 [VGA controller])
+ca:00.0 Display controller [0380]: Advanced Micro Devices, Inc. [AMD/ATI] Device [1002:74a1]
+d1:00.0 Processing accelerators [1200]: Advanced Micro Devices, Inc. [AMD/ATI] Arcturus GL-XL [Instinct MI100] [1002:738c] (rev 01)
+`
+
+func TestParseLSPCI(t *testing.T) {
+	got, err := parseLSPCI(strings.NewReader(lspciOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, d := range got {
+		ids = append(ids, d.slot+" "+d.class+" "+d.vendorID+":"+d.deviceID)
+	}
+	want := []string{
+		"02:00.0 0300 1a03:2000",
+		"0000:17:00.0 0302 10de:20b5",
+		"65:00.0 0300 10de:2684",
+		"ca:00.0 0380 1002:74a1",
+		"d1:00.0 1200 1002:738c",
+	}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("parseLSPCI() = %v, want %v", ids, want)
+	}
+
+	if got[4].desc != "Advanced Micro Devices, Inc. [AMD/ATI] Arcturus GL-XL [Instinct MI100]" {
+		t.Errorf("parseLSPCI() description = %q", got[4].desc)
+	}
+}
+
+func TestReport(t *testing.T) {
+	data, err := os.ReadFile("../../devices/pcie/gpus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpus, err := schema.ParseGPUs(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices, err := parseLSPCI(strings.NewReader(lspciOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	report(&buf, devices, gpus, false)
+
+	want := `advertisable:
+	0000:17:00.0	10de:20b5	nvidia a100 80Gi PCIe
+	65:00.0	10de:2684	nvidia rtx4090 24Gi PCIe
+	d1:00.0	1002:738c	amd mi100 32Gi PCIe
+not in catalog:
+	02:00.0	1a03:2000	ASPEED Technology, Inc. ASPEED Graphics Family (vendor not in catalog)
+	ca:00.0	1002:74a1	Advanced Micro Devices, Inc. [AMD/ATI] Device
+`
+	if buf.String() != want {
+		t.Errorf("report() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	report(&buf, nil, gpus, false)
+	if buf.String() != "no display controllers or processing accelerators found\n" {
+		t.Errorf("report() with no devices = %q", buf.String())
+	}
+}
+
+func TestIssueURL(t *testing.T) {
+	d := pciDevice{vendorID: "1002", deviceID: "74a1", line: "ca:00.0 Display controller [0380]: AMD Device [1002:74a1]"}
+
+	u, err := url.Parse(issueURL(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "github.com" || u.Path != "/akash-network/provider-configs/issues/new" {
+		t.Errorf("issueURL() = %s", u)
+	}
+	q := u.Query()
+	if q.Get("title") != "Add GPU 1002:74a1" || !strings.Contains(q.Get("body"), d.line) {
+		t.Errorf("issueURL() query = %v", q)
+	}
+}
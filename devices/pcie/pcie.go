@@ -0,0 +1,11 @@
+// Package pcie embeds the PCIe device catalogs in this directory, so Go
+// consumers can use the copy that matches the module version they build
+// against.
+package pcie
+
+import _ "embed"
+
+// GPUsSchema is gpus.schema.json, the JSON Schema for gpus.json.
+//
+//go:embed gpus.schema.json
+var GPUsSchema []byte
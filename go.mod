@@ -0,0 +1,3 @@
+module github.com/akash-network/provider-configs
+
+go 1.22
@@ -0,0 +1,157 @@
+// Package pciids looks up PCI vendor, device, and subsystem names in the
+// pci.ids database format used by lspci, https://pci-ids.ucw.cz/.
+//
+// Default returns the excerpt embedded in this package, which covers the
+// vendors allowed in the GPU catalog. Load and Parse read a full database,
+// such as /usr/share/hwdata/pci.ids, for device and subsystem names.
+package pciids
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// DB is a parsed pci.ids database.
+type DB struct {
+	vendors map[string]Vendor
+}
+
+// Vendor is a PCI vendor and its devices, keyed by lowercase device ID.
+type Vendor struct {
+	ID      string
+	Name    string
+	Devices map[string]Device
+}
+
+// Device is a PCI device and its subsystems, keyed by lowercase
+// "subvendor:subdevice".
+type Device struct {
+	ID         string
+	Name       string
+	Subsystems map[string]string
+}
+
+//go:embed pci.ids
+var excerpt string
+
+var defaultDB = sync.OnceValue(func() *DB {
+	db, err := Parse(strings.NewReader(excerpt))
+	if err != nil {
+		panic("pciids: embedded database: " + err.Error())
+	}
+	return db
+})
+
+// Default returns the embedded database excerpt.
+func Default() *DB {
+	return defaultDB()
+}
+
+// Load reads and parses the database at path.
+func Load(path string) (*DB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	db, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+var (
+	vendorLine    = regexp.MustCompile(`^([0-9a-fA-F]{4})  (.+)$`)
+	deviceLine    = regexp.MustCompile(`^\t([0-9a-fA-F]{4})  (.+)$`)
+	subsystemLine = regexp.MustCompile(`^\t\t([0-9a-fA-F]{4}) ([0-9a-fA-F]{4})  (.+)$`)
+)
+
+// Parse parses a database in pci.ids format. The device class section that
+// follows the vendors is skipped.
+func Parse(r io.Reader) (*DB, error) {
+	db := &DB{vendors: make(map[string]Vendor)}
+
+	// The device and subsystem maps of the last vendor and device seen.
+	var devices map[string]Device
+	var subsystems map[string]string
+	inClasses := false
+
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimRight(sc.Text(), " \r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "C ") {
+			inClasses = true
+			continue
+		}
+		if inClasses && strings.HasPrefix(line, "\t") {
+			continue
+		}
+		inClasses = false
+
+		if m := vendorLine.FindStringSubmatch(line); m != nil {
+			id := strings.ToLower(m[1])
+			if _, ok := db.vendors[id]; ok {
+				return nil, fmt.Errorf("line %d: duplicate vendor %s", n, id)
+			}
+			devices, subsystems = make(map[string]Device), nil
+			db.vendors[id] = Vendor{ID: id, Name: m[2], Devices: devices}
+			continue
+		}
+
+		if m := deviceLine.FindStringSubmatch(line); m != nil {
+			if devices == nil {
+				return nil, fmt.Errorf("line %d: device before any vendor", n)
+			}
+			id := strings.ToLower(m[1])
+			if _, ok := devices[id]; ok {
+				return nil, fmt.Errorf("line %d: duplicate device %s", n, id)
+			}
+			subsystems = make(map[string]string)
+			devices[id] = Device{ID: id, Name: m[2], Subsystems: subsystems}
+			continue
+		}
+
+		if m := subsystemLine.FindStringSubmatch(line); m != nil {
+			if subsystems == nil {
+				return nil, fmt.Errorf("line %d: subsystem before any device", n)
+			}
+			subsystems[strings.ToLower(m[1]+":"+m[2])] = m[3]
+			continue
+		}
+
+		return nil, fmt.Errorf("line %d: malformed entry %q", n, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Vendor looks up a vendor by ID, in any case.
+func (db *DB) Vendor(id string) (Vendor, bool) {
+	v, ok := db.vendors[strings.ToLower(id)]
+	return v, ok
+}
+
+// Device looks up a device by vendor and device ID, in any case.
+func (db *DB) Device(vendorID, deviceID string) (Device, bool) {
+	v, ok := db.Vendor(vendorID)
+	if !ok {
+		return Device{}, false
+	}
+	d, ok := v.Devices[strings.ToLower(deviceID)]
+	return d, ok
+}
@@ -0,0 +1,113 @@
+package pciids
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sample = `# comment
+#	vendor  vendor_name
+
+10de  NVIDIA Corporation
+	20b5  GA100 [A100 PCIe 80GB]
+		10de 1533  A100 80GB PCIe
+	2684  AD102 [GeForce RTX 4090]
+1002  Advanced Micro Devices, Inc. [AMD/ATI]
+	738C  Arcturus GL-XL [Instinct MI100]
+
+C 03  Display controller
+	02  3D controller
+		00  VGA controller
+ffff  Illegal Vendor ID
+`
+
+func TestParse(t *testing.T) {
+	db, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := Device{ID: "20b5", Name: "GA100 [A100 PCIe 80GB]", Subsystems: map[string]string{
+		"10de:1533": "A100 80GB PCIe",
+	}}
+	if d, ok := db.Device("10DE", "20B5"); !ok || !reflect.DeepEqual(d, want) {
+		t.Errorf("Device(10DE, 20B5) = %+v, %v, want %+v", d, ok, want)
+	}
+	if d, ok := db.Device("1002", "738c"); !ok || d.Name != "Arcturus GL-XL [Instinct MI100]" {
+		t.Errorf("Device(1002, 738c) = %+v, %v", d, ok)
+	}
+	if v, ok := db.Vendor("10de"); !ok || v.Name != "NVIDIA Corporation" || len(v.Devices) != 2 {
+		t.Errorf("Vendor(10de) = %+v, %v", v, ok)
+	}
+	if v, ok := db.Vendor("ffff"); !ok || len(v.Devices) != 0 {
+		t.Errorf("Vendor(ffff) after the class section = %+v, %v", v, ok)
+	}
+
+	if _, ok := db.Vendor("8086"); ok {
+		t.Error("Vendor(8086) found, want missing")
+	}
+	if _, ok := db.Device("10de", "ffff"); ok {
+		t.Error("Device(10de, ffff) found, want missing")
+	}
+	if _, ok := db.Device("8086", "20b5"); ok {
+		t.Error("Device(8086, 20b5) found, want missing")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"device before vendor", "\t20b5  A100\n", "line 1: device before any vendor"},
+		{"subsystem before device", "10de  NVIDIA\n\t\t10de 1533  A100\n", "line 2: subsystem before any device"},
+		{"duplicate vendor", "10de  NVIDIA\n10DE  NVIDIA\n", "line 2: duplicate vendor 10de"},
+		{"duplicate device", "10de  NVIDIA\n\t20b5  A100\n\t20b5  A100\n", "line 3: duplicate device 20b5"},
+		{"malformed", "10de NVIDIA\n", `line 1: malformed entry "10de NVIDIA"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.data))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	for id, name := range map[string]string{
+		"1002": "Advanced Micro Devices, Inc. [AMD/ATI]",
+		"10de": "NVIDIA Corporation",
+		"8086": "Intel Corporation",
+	} {
+		if v, ok := Default().Vendor(id); !ok || v.Name != name {
+			t.Errorf("Default().Vendor(%s) = %+v, %v, want name %q", id, v, ok, name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if _, err := Load("/nonexistent/pci.ids"); err == nil {
+		t.Error("Load() error = nil, want not-exist error")
+	}
+
+	for _, path := range []string{"/usr/share/hwdata/pci.ids", "/usr/share/misc/pci.ids"} {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		db, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", path, err)
+		}
+		if _, ok := db.Vendor("10de"); !ok {
+			t.Errorf("Load(%s) has no vendor 10de", path)
+		}
+		return
+	}
+	t.Log("no system pci.ids found; skipping the full database")
+}
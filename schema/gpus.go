@@ -0,0 +1,200 @@
+// Package schema defines Go types for the device catalogs in this repository,
+// so provider-services, the inventory operator, and other consumers can
+// import them instead of re-declaring the structs.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/akash-network/provider-configs/devices/pcie"
+	"github.com/akash-network/provider-configs/units"
+	"github.com/akash-network/provider-configs/validate"
+)
+
+// GPUs is the PCIe GPU catalog, devices/pcie/gpus.json, keyed by PCI vendor
+// ID.
+type GPUs map[string]VendorDevices
+
+// VendorDevices is one vendor's entry in GPUs, with its devices keyed by PCI
+// device ID.
+type VendorDevices struct {
+	Name    string                `json:"name"`
+	Devices map[string]PCIeDevice `json:"devices"`
+}
+
+// PCIeDevice is one GPU model. Every field after MemorySize is optional.
+type PCIeDevice struct {
+	Name                  string    `json:"name"`
+	Aliases               []string  `json:"aliases,omitempty"`
+	Interface             string    `json:"interface"`
+	MemorySize            string    `json:"memory_size"`
+	CUDAComputeCapability string    `json:"cuda_compute_capability,omitempty"`
+	Architecture          string    `json:"architecture,omitempty"`
+	FormFactor            string    `json:"form_factor,omitempty"`
+	PCIeGeneration        int       `json:"pcie_generation,omitempty"`
+	Lanes                 int       `json:"lanes,omitempty"`
+	MaxPowerWatts         int       `json:"max_power_watts,omitempty"`
+	Deprecated            bool      `json:"deprecated,omitempty"`
+	EOLDate               string    `json:"eol_date,omitempty"`
+	SupersededBy          string    `json:"superseded_by,omitempty"`
+	Profiles              *Profiles `json:"profiles,omitempty"`
+}
+
+// Profiles lists the ways a device can be partitioned between workloads.
+type Profiles struct {
+	MIG  []MIGProfile  `json:"mig,omitempty"`
+	VGPU []VGPUProfile `json:"vgpu,omitempty"`
+}
+
+// MIGProfile is an NVIDIA Multi-Instance GPU profile, e.g. 1g.5gb.
+type MIGProfile struct {
+	Name         string `json:"name"`
+	MemorySize   string `json:"memory_size"`
+	MaxInstances int    `json:"max_instances"`
+}
+
+// VGPUProfile is a vGPU type, e.g. A100-4C.
+type VGPUProfile struct {
+	Name         string `json:"name"`
+	MemorySize   string `json:"memory_size"`
+	MaxInstances int    `json:"max_instances,omitempty"`
+}
+
+var gpusSchema = sync.OnceValues(func() (*validate.Schema, error) {
+	return validate.ParseSchema(pcie.GPUsSchema)
+})
+
+// ParseGPUs decodes a GPU catalog. Unknown fields and duplicate keys are
+// errors, since decoding would otherwise drop them silently. The result is
+// not otherwise validated; see GPUs.Validate.
+func ParseGPUs(data []byte) (GPUs, error) {
+	var errs []error
+	for _, e := range validate.Bytes(data, nil) {
+		errs = append(errs, e)
+	}
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	var g GPUs
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&g); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Marshal encodes g in the layout used by the catalog files: keys sorted,
+// fields in schema order, two-space indentation, and a trailing newline.
+func (g GPUs) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(g); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate checks g against the catalog schema and the catalog-wide rules in
+// validate.GPUChecks.
+func (g GPUs) Validate() ([]validate.Error, error) {
+	sch, err := gpusSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := g.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return validate.Bytes(data, sch, validate.GPUChecks...), nil
+}
+
+// Device looks up a device by PCI vendor and device ID, in any case.
+func (g GPUs) Device(vendorID, deviceID string) (PCIeDevice, bool) {
+	d, ok := g[strings.ToLower(vendorID)].Devices[strings.ToLower(deviceID)]
+	return d, ok
+}
+
+// Canonicalize returns a copy of g with lowercase PCI IDs and memory sizes
+// in canonical units, e.g. "16384 MiB" as "16Gi". It fails if two IDs
+// differ only in case or a memory size cannot be parsed.
+func (g GPUs) Canonicalize() (GPUs, error) {
+	res := make(GPUs, len(g))
+
+	for vendorID, vendor := range g {
+		id := strings.ToLower(vendorID)
+		if _, ok := res[id]; ok {
+			return nil, fmt.Errorf("vendor %s: duplicate vendor ID %s", vendorID, id)
+		}
+
+		devices := make(map[string]PCIeDevice, len(vendor.Devices))
+		for deviceID, device := range vendor.Devices {
+			did := strings.ToLower(deviceID)
+			if _, ok := devices[did]; ok {
+				return nil, fmt.Errorf("device %s:%s: duplicate device ID %s", vendorID, deviceID, did)
+			}
+
+			device, err := device.canonicalize()
+			if err != nil {
+				return nil, fmt.Errorf("device %s:%s: %w", vendorID, deviceID, err)
+			}
+			devices[did] = device
+		}
+
+		res[id] = VendorDevices{Name: vendor.Name, Devices: devices}
+	}
+
+	return res, nil
+}
+
+func (d PCIeDevice) canonicalize() (PCIeDevice, error) {
+	var err error
+
+	if d.MemorySize, err = canonicalSize(d.MemorySize); err != nil {
+		return d, err
+	}
+	d.SupersededBy = strings.ToLower(d.SupersededBy)
+	d.Aliases = append([]string(nil), d.Aliases...)
+
+	if d.Profiles != nil {
+		p := Profiles{
+			MIG:  append([]MIGProfile(nil), d.Profiles.MIG...),
+			VGPU: append([]VGPUProfile(nil), d.Profiles.VGPU...),
+		}
+		for i := range p.MIG {
+			if p.MIG[i].MemorySize, err = canonicalSize(p.MIG[i].MemorySize); err != nil {
+				return d, fmt.Errorf("MIG profile %s: %w", p.MIG[i].Name, err)
+			}
+		}
+		for i := range p.VGPU {
+			if p.VGPU[i].MemorySize, err = canonicalSize(p.VGPU[i].MemorySize); err != nil {
+				return d, fmt.Errorf("vGPU profile %s: %w", p.VGPU[i].Name, err)
+			}
+		}
+		d.Profiles = &p
+	}
+
+	return d, nil
+}
+
+func canonicalSize(s string) (string, error) {
+	n, err := units.ParseLenient(s)
+	if err != nil {
+		return "", err
+	}
+	return units.Format(n), nil
+}
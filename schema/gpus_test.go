@@ -0,0 +1,219 @@
+package schema
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readCatalog(t *testing.T) GPUs {
+	t.Helper()
+
+	data, err := os.ReadFile("../devices/pcie/gpus.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := ParseGPUs(data)
+	if err != nil {
+		t.Fatalf("ParseGPUs() error = %v", err)
+	}
+	return g
+}
+
+func TestCatalog(t *testing.T) {
+	g := readCatalog(t)
+
+	errs, err := g.Validate()
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	for _, e := range errs {
+		t.Error(e)
+	}
+
+	d, ok := g.Device("10DE", "20B5")
+	want := PCIeDevice{Name: "a100", Interface: "PCIe", MemorySize: "80Gi"}
+	if !ok || !reflect.DeepEqual(d, want) {
+		t.Errorf("Device(10DE, 20B5) = %+v, %v, want %+v", d, ok, want)
+	}
+	if _, ok := g.Device("10de", "ffff"); ok {
+		t.Error("Device(10de, ffff) found, want missing")
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	g := readCatalog(t)
+
+	data, err := g.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseGPUs(data)
+	if err != nil {
+		t.Fatalf("ParseGPUs(Marshal()) error = %v", err)
+	}
+	if !reflect.DeepEqual(got, g) {
+		t.Error("ParseGPUs(Marshal()) differs from the original")
+	}
+
+	again, err := got.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(data) {
+		t.Error("Marshal() is not stable across a round trip")
+	}
+}
+
+func TestMarshalLayout(t *testing.T) {
+	g := GPUs{
+		"10de": {Name: "nvidia", Devices: map[string]PCIeDevice{
+			"20b5": {Name: "a100", Interface: "PCIe", MemorySize: "80Gi", Lanes: 16,
+				Profiles: &Profiles{MIG: []MIGProfile{{Name: "1g.10gb+me", MemorySize: "10Gi", MaxInstances: 1}}}},
+			"1eb8": {Name: "t4", Interface: "PCIe", MemorySize: "16Gi"},
+		}},
+	}
+
+	want := `{
+  "10de": {
+    "name": "nvidia",
+    "devices": {
+      "1eb8": {
+        "name": "t4",
+        "interface": "PCIe",
+        "memory_size": "16Gi"
+      },
+      "20b5": {
+        "name": "a100",
+        "interface": "PCIe",
+        "memory_size": "80Gi",
+        "lanes": 16,
+        "profiles": {
+          "mig": [
+            {
+              "name": "1g.10gb+me",
+              "memory_size": "10Gi",
+              "max_instances": 1
+            }
+          ]
+        }
+      }
+    }
+  }
+}
+`
+	data, err := g.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestParseGPUsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unknown field", `{"10de":{"name":"nvidia","devices":{"2235":{"name":"a40","memroy_size":"48Gi"}}}}`, `json: unknown field "memroy_size"`},
+		{"duplicate device", `{"10de":{"name":"nvidia","devices":{"2235":{},"2235":{}}}}`, `/10de/devices/2235: duplicate key "2235"`},
+		{"wrong type", `{"10de":{"name":"nvidia","devices":{"2235":{"lanes":"16"}}}}`, "cannot unmarshal string"},
+		{"empty", ``, "invalid JSON: empty document"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseGPUs([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseGPUs() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	g := GPUs{
+		"10de": {Name: "amd", Devices: map[string]PCIeDevice{
+			"2235": {Name: "a40", Interface: "pcie", MemorySize: "48GB"},
+		}},
+	}
+
+	errs, err := g.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Pointer)
+	}
+	want := []string{"/10de/devices/2235/interface", "/10de/devices/2235/memory_size", "/10de/name"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() pointers = %v, want %v", got, want)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	g := GPUs{
+		"10DE": {Name: "nvidia", Devices: map[string]PCIeDevice{
+			"20B5": {Name: "a100", Interface: "PCIe", MemorySize: "81920 MiB", SupersededBy: "10DE:2330",
+				Profiles: &Profiles{
+					MIG:  []MIGProfile{{Name: "1g.10gb", MemorySize: "10 GB", MaxInstances: 7}},
+					VGPU: []VGPUProfile{{Name: "A100D-4C", MemorySize: "4096MiB"}},
+				}},
+		}},
+	}
+
+	got, err := g.Canonicalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := GPUs{
+		"10de": {Name: "nvidia", Devices: map[string]PCIeDevice{
+			"20b5": {Name: "a100", Interface: "PCIe", MemorySize: "80Gi", SupersededBy: "10de:2330",
+				Profiles: &Profiles{
+					MIG:  []MIGProfile{{Name: "1g.10gb", MemorySize: "10Gi", MaxInstances: 7}},
+					VGPU: []VGPUProfile{{Name: "A100D-4C", MemorySize: "4Gi"}},
+				}},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Canonicalize() = %+v, want %+v", got, want)
+	}
+
+	if g["10DE"].Devices["20B5"].Profiles.MIG[0].MemorySize != "10 GB" {
+		t.Error("Canonicalize() modified its receiver")
+	}
+}
+
+func TestCanonicalizeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		g    GPUs
+		want string
+	}{
+		{"vendor IDs differ in case", GPUs{"10de": {}, "10DE": {}}, "duplicate vendor ID 10de"},
+		{"device IDs differ in case", GPUs{"10de": {Devices: map[string]PCIeDevice{
+			"20b5": {MemorySize: "80Gi"}, "20B5": {MemorySize: "80Gi"},
+		}}}, "duplicate device ID 20b5"},
+		{"bad memory size", GPUs{"10de": {Devices: map[string]PCIeDevice{
+			"20b5": {MemorySize: "lots"},
+		}}}, `device 10de:20b5: invalid memory size "lots"`},
+		{"bad profile size", GPUs{"10de": {Devices: map[string]PCIeDevice{
+			"20b5": {MemorySize: "80Gi", Profiles: &Profiles{MIG: []MIGProfile{{Name: "1g.10gb", MemorySize: "ten"}}}},
+		}}}, `device 10de:20b5: MIG profile 1g.10gb: invalid memory size "ten"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.g.Canonicalize()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Canonicalize() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
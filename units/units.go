@@ -0,0 +1,126 @@
+// Package units parses and formats device memory sizes.
+//
+// The canonical form used in the catalogs is a positive integer followed by
+// a Kubernetes binary suffix, e.g. "24Gi". Parse accepts only that form.
+// ParseLenient also accepts the spellings found in vendor spec sheets and
+// hardware submissions, such as "24 GiB", "80GB", or "16384 MiB", so they can
+// be normalized with Format.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Binary multiples of a byte.
+const (
+	Ki uint64 = 1 << (10 * (iota + 1))
+	Mi
+	Gi
+	Ti
+	Pi
+)
+
+// ErrInvalid is wrapped by every error returned from Parse and ParseLenient.
+var ErrInvalid = errors.New("invalid memory size")
+
+var suffixes = []struct {
+	name string
+	size uint64
+}{
+	{"Pi", Pi},
+	{"Ti", Ti},
+	{"Gi", Gi},
+	{"Mi", Mi},
+	{"Ki", Ki},
+}
+
+// lenientSuffixes maps lower-cased suffixes accepted by ParseLenient to their
+// binary multiple. Decimal spellings mean binary sizes here: GPU vendors quote
+// memory as e.g. "80GB" for a card with 80 GiB, and reading it as 10^9 bytes
+// would produce a size no catalog entry could express.
+var lenientSuffixes = map[string]uint64{
+	"ki": Ki, "kib": Ki, "k": Ki, "kb": Ki,
+	"mi": Mi, "mib": Mi, "m": Mi, "mb": Mi,
+	"gi": Gi, "gib": Gi, "g": Gi, "gb": Gi,
+	"ti": Ti, "tib": Ti, "t": Ti, "tb": Ti,
+	"pi": Pi, "pib": Pi, "p": Pi, "pb": Pi,
+}
+
+// Parse parses a size in canonical form and returns it in bytes.
+func Parse(s string) (uint64, error) {
+	for _, suffix := range suffixes {
+		digits, ok := strings.CutSuffix(s, suffix.name)
+		if !ok {
+			continue
+		}
+		if digits == "" || digits[0] == '0' || strings.Trim(digits, "0123456789") != "" {
+			break
+		}
+
+		n, _ := new(big.Int).SetString(digits, 10)
+		return scale(s, new(big.Rat).SetInt(n), suffix.size)
+	}
+
+	return 0, invalid(s, "want a positive integer followed by Ki, Mi, Gi, Ti, or Pi")
+}
+
+// ParseLenient parses a size written with optional whitespace, a decimal
+// fraction, and any of the suffixes K, M, G, T, P in the forms "G", "Gi",
+// "GiB", or "GB", case-insensitively. Decimal suffixes are read as binary;
+// see the package documentation. The result must be a positive whole number
+// of bytes.
+func ParseLenient(s string) (uint64, error) {
+	t := strings.TrimSpace(s)
+
+	i := strings.LastIndexAny(t, "0123456789.") + 1
+	number, suffix := strings.TrimSpace(t[:i]), strings.ToLower(strings.TrimSpace(t[i:]))
+
+	size, ok := lenientSuffixes[suffix]
+	if !ok {
+		return 0, invalid(s, "missing or unknown unit")
+	}
+	if number == "" || strings.Trim(number, "0123456789.") != "" || strings.Count(number, ".") > 1 {
+		return 0, invalid(s, "malformed number")
+	}
+
+	n, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return 0, invalid(s, "malformed number")
+	}
+	return scale(s, n, size)
+}
+
+// Format renders n in canonical form with the largest suffix that represents
+// it exactly, e.g. 17179869184 as "16Gi". A size that is not a whole number
+// of KiB has no canonical form and is written as a plain byte count.
+func Format(n uint64) string {
+	if n != 0 {
+		for _, suffix := range suffixes {
+			if n%suffix.size == 0 {
+				return fmt.Sprintf("%d%s", n/suffix.size, suffix.name)
+			}
+		}
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func scale(s string, n *big.Rat, size uint64) (uint64, error) {
+	n.Mul(n, new(big.Rat).SetInt(new(big.Int).SetUint64(size)))
+
+	switch {
+	case n.Sign() <= 0:
+		return 0, invalid(s, "must be positive")
+	case !n.IsInt():
+		return 0, invalid(s, "not a whole number of bytes")
+	case !n.Num().IsUint64():
+		return 0, invalid(s, "too large")
+	}
+	return n.Num().Uint64(), nil
+}
+
+func invalid(s, reason string) error {
+	return fmt.Errorf("%w %q: %s", ErrInvalid, s, reason)
+}
@@ -0,0 +1,154 @@
+package units
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint64
+	}{
+		{"1Ki", Ki},
+		{"512Mi", 512 * Mi},
+		{"24Gi", 24 * Gi},
+		{"80Gi", 80 * Gi},
+		{"2Ti", 2 * Ti},
+		{"1Pi", Pi},
+		{"16384Mi", 16 * Gi},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"24",
+		"Gi",
+		"0Gi",
+		"024Gi",
+		"-1Gi",
+		"1.5Gi",
+		"24 Gi",
+		" 24Gi",
+		"24gi",
+		"24GiB",
+		"24GB",
+		"24G",
+		"+24Gi",
+		"16384Pi",
+		"99999999999999999999999Pi",
+	} {
+		if got, err := Parse(in); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) = %d, %v, want ErrInvalid", in, got, err)
+		}
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint64
+	}{
+		{"24Gi", 24 * Gi},
+		{"24 GiB", 24 * Gi},
+		{"24 gib", 24 * Gi},
+		{"80GB", 80 * Gi},
+		{"80 gb", 80 * Gi},
+		{"16G", 16 * Gi},
+		{"16384 MiB", 16 * Gi},
+		{"16384MB", 16 * Gi},
+		{"1.5 GiB", 1536 * Mi},
+		{"0.5Ti", 512 * Gi},
+		{".5 TB", 512 * Gi},
+		{"  48 Gi  ", 48 * Gi},
+		{"4 KB", 4 * Ki},
+		{"1 PiB", Pi},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLenient(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLenient(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseLenientInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"24",
+		"GiB",
+		"0 GB",
+		"-1 GB",
+		"1.2.3 GB",
+		"24 XB",
+		"24 bytes",
+		"1e3 GB",
+		"0.0000001 Ki",
+		"20000 PB",
+	} {
+		if got, err := ParseLenient(in); !errors.Is(err, ErrInvalid) {
+			t.Errorf("ParseLenient(%q) = %d, %v, want ErrInvalid", in, got, err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in   uint64
+		want string
+	}{
+		{0, "0"},
+		{1, "1"},
+		{1023, "1023"},
+		{Ki, "1Ki"},
+		{1536 * Mi, "1536Mi"},
+		{16 * Gi, "16Gi"},
+		{80 * Gi, "80Gi"},
+		{1024 * Gi, "1Ti"},
+		{3 * Pi, "3Pi"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.in); got != tt.want {
+			t.Errorf("Format(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	for _, in := range []string{"1Ki", "6Gi", "48Gi", "1536Mi", "2Ti"} {
+		n, err := Parse(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Format(n); got != in {
+			t.Errorf("Format(Parse(%q)) = %q", in, got)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"16384 MiB": "16Gi",
+		"80GB":      "80Gi",
+		"24 GiB":    "24Gi",
+		"1.5 GiB":   "1536Mi",
+	} {
+		n, err := ParseLenient(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Format(n); got != want {
+			t.Errorf("Format(ParseLenient(%q)) = %q, want %q", in, got, want)
+		}
+	}
+}
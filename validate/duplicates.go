@@ -0,0 +1,69 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// duplicateKeys reports every object key that appears more than once in its
+// object. data must already be known to be valid JSON.
+func duplicateKeys(data []byte) []Error {
+	var res []Error
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	// data has been decoded successfully, so the walk cannot fail.
+	_ = walkDuplicates(dec, "", &res)
+
+	return res
+}
+
+func walkDuplicates(dec *json.Decoder, ptr string, res *[]Error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		seen := make(map[string]bool)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			key := tok.(string)
+			child := ptr + "/" + escapePointer(key)
+			if seen[key] {
+				*res = append(*res, Error{
+					Pointer: child,
+					Message: "duplicate key " + strconv.Quote(key) + "; only the last occurrence is kept",
+				})
+			}
+			seen[key] = true
+
+			if err := walkDuplicates(dec, child, res); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := walkDuplicates(dec, ptr+"/"+strconv.Itoa(i), res); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	}
+
+	return err
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapePointer(token string) string {
+	return pointerEscaper.Replace(token)
+}
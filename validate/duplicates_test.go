@@ -0,0 +1,49 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []Error
+	}{
+		{"none", `{"a":{"a":1},"b":[{"a":1},{"a":2}]}`, nil},
+		{"top level", `{"10de":{},"10de":{}}`, []Error{
+			{Pointer: "/10de", Message: `duplicate key "10de"; only the last occurrence is kept`},
+		}},
+		{"vendor field", `{"1002":{"name":"amd","devices":{},"name":"amd"}}`, []Error{
+			{Pointer: "/1002/name", Message: `duplicate key "name"; only the last occurrence is kept`},
+		}},
+		{"device ID", `{"10de":{"devices":{"20b1":{},"20b5":{},"20b1":{}}}}`, []Error{
+			{Pointer: "/10de/devices/20b1", Message: `duplicate key "20b1"; only the last occurrence is kept`},
+		}},
+		{"inside array", `{"profiles":{"mig":[{"name":"a"},{"name":"b","name":"c"}]}}`, []Error{
+			{Pointer: "/profiles/mig/1/name", Message: `duplicate key "name"; only the last occurrence is kept`},
+		}},
+		{"every repeat", `{"a":1,"a":2,"a":3}`, []Error{
+			{Pointer: "/a", Message: `duplicate key "a"; only the last occurrence is kept`},
+			{Pointer: "/a", Message: `duplicate key "a"; only the last occurrence is kept`},
+		}},
+		{"escaped pointer", `{"a/b":{"~":1,"~":2}}`, []Error{
+			{Pointer: "/a~1b/~0", Message: `duplicate key "~"; only the last occurrence is kept`},
+		}},
+		{"several depths", `{"x":{"y":{"z":1,"z":1},"y":{}},"x":1}`, []Error{
+			{Pointer: "/x/y/z", Message: `duplicate key "z"; only the last occurrence is kept`},
+			{Pointer: "/x/y", Message: `duplicate key "y"; only the last occurrence is kept`},
+			{Pointer: "/x", Message: `duplicate key "x"; only the last occurrence is kept`},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Bytes([]byte(tt.doc), nil)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bytes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
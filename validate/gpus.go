@@ -0,0 +1,274 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akash-network/provider-configs/pciids"
+	"github.com/akash-network/provider-configs/units"
+)
+
+// GPUChecks are the catalog-wide rules for devices/pcie/gpus.json.
+var GPUChecks = []Check{
+	checkVendors,
+	checkProfileMemory,
+	checkLifecycle,
+	checkAliases,
+}
+
+var (
+	pciIDPattern     = regexp.MustCompile(`^[0-9a-f]{4}$`)
+	datePattern      = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+	deviceRefPattern = regexp.MustCompile(`^[0-9a-f]{4}:[0-9a-f]{4}$`)
+)
+
+type gpuDevice struct {
+	vendorID string
+	deviceID string
+	vendor   map[string]any
+	device   map[string]any
+}
+
+func (d gpuDevice) pointer() string {
+	return vendorPointer(d.vendorID) + "/devices/" + escapePointer(d.deviceID)
+}
+
+func vendorPointer(vendorID string) string {
+	return "/" + escapePointer(vendorID)
+}
+
+// gpuVendors returns the vendor IDs of a GPU catalog in sorted order, and the
+// vendor objects they map to. Entries that are not objects are skipped.
+func gpuVendors(doc any) ([]string, map[string]map[string]any) {
+	root, _ := doc.(map[string]any)
+
+	ids := make([]string, 0, len(root))
+	vendors := make(map[string]map[string]any, len(root))
+	for id, v := range root {
+		if vendor, ok := v.(map[string]any); ok {
+			ids = append(ids, id)
+			vendors[id] = vendor
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, vendors
+}
+
+// gpuDevices returns every device of a GPU catalog, ordered by vendor ID and
+// then device ID. Entries that are not objects are skipped.
+func gpuDevices(doc any) []gpuDevice {
+	var res []gpuDevice
+
+	ids, vendors := gpuVendors(doc)
+	for _, vendorID := range ids {
+		devices, _ := vendors[vendorID]["devices"].(map[string]any)
+
+		deviceIDs := make([]string, 0, len(devices))
+		for id := range devices {
+			deviceIDs = append(deviceIDs, id)
+		}
+		sort.Strings(deviceIDs)
+
+		for _, deviceID := range deviceIDs {
+			if device, ok := devices[deviceID].(map[string]any); ok {
+				res = append(res, gpuDevice{
+					vendorID: vendorID,
+					deviceID: deviceID,
+					vendor:   vendors[vendorID],
+					device:   device,
+				})
+			}
+		}
+	}
+
+	return res
+}
+
+// checkVendors reports vendor keys that are not in the PCI vendor table, and
+// vendor names that differ from the name the table assigns to the ID.
+// Malformed keys are left to the schema.
+func checkVendors(doc any) []Error {
+	var res []Error
+
+	ids, vendors := gpuVendors(doc)
+	for _, id := range ids {
+		if !pciIDPattern.MatchString(id) {
+			continue
+		}
+
+		want, ok := pciVendors[id]
+		if !ok {
+			res = append(res, Error{
+				Pointer: vendorPointer(id),
+				Message: fmt.Sprintf("unknown PCI vendor ID %s; add it to pciVendors in validate/vendors.go if it is registered with PCI-SIG", id),
+			})
+			continue
+		}
+
+		if name, ok := vendors[id]["name"].(string); ok && name != want {
+			registered, _ := pciids.Default().Vendor(id)
+			res = append(res, Error{
+				Pointer: vendorPointer(id) + "/name",
+				Message: fmt.Sprintf("PCI vendor %s is %s, want name %q, got %q", id, registered.Name, want, name),
+			})
+		}
+	}
+
+	return res
+}
+
+// checkProfileMemory reports MIG and vGPU profiles that claim more memory than
+// the device they partition. Sizes that do not parse are left to the schema.
+func checkProfileMemory(doc any) []Error {
+	var res []Error
+
+	for _, d := range gpuDevices(doc) {
+		deviceSize, ok := d.device["memory_size"].(string)
+		if !ok {
+			continue
+		}
+		deviceBytes, err := units.Parse(deviceSize)
+		if err != nil {
+			continue
+		}
+
+		profiles, _ := d.device["profiles"].(map[string]any)
+		for _, kind := range []string{"mig", "vgpu"} {
+			list, _ := profiles[kind].([]any)
+			for i, p := range list {
+				profile, _ := p.(map[string]any)
+				size, ok := profile["memory_size"].(string)
+				if !ok {
+					continue
+				}
+				if n, err := units.Parse(size); err == nil && n > deviceBytes {
+					res = append(res, Error{
+						Pointer: d.pointer() + "/profiles/" + kind + "/" + strconv.Itoa(i) + "/memory_size",
+						Message: fmt.Sprintf("profile memory %s exceeds device memory %s", size, deviceSize),
+					})
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// checkLifecycle reports eol_date values that are not real calendar dates and
+// superseded_by references that do not name another device in the catalog.
+// Values that do not match the schema's format are left to the schema.
+func checkLifecycle(doc any) []Error {
+	var res []Error
+
+	devices := gpuDevices(doc)
+	exists := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		exists[d.vendorID+":"+d.deviceID] = true
+	}
+
+	for _, d := range devices {
+		if date, ok := d.device["eol_date"].(string); ok && datePattern.MatchString(date) {
+			if _, err := time.Parse(time.DateOnly, date); err != nil {
+				res = append(res, Error{
+					Pointer: d.pointer() + "/eol_date",
+					Message: fmt.Sprintf("%q is not a valid date", date),
+				})
+			}
+		}
+
+		ref, ok := d.device["superseded_by"].(string)
+		if !ok || !deviceRefPattern.MatchString(ref) {
+			continue
+		}
+		vendorID, deviceID, _ := strings.Cut(ref, ":")
+		switch {
+		case vendorID == d.vendorID && deviceID == d.deviceID:
+			res = append(res, Error{
+				Pointer: d.pointer() + "/superseded_by",
+				Message: "device cannot supersede itself",
+			})
+		case !exists[ref]:
+			res = append(res, Error{
+				Pointer: d.pointer() + "/superseded_by",
+				Message: fmt.Sprintf("%s is not in the catalog", ref),
+			})
+		}
+	}
+
+	return res
+}
+
+// checkAliases reports aliases that would resolve to more than one model: an
+// alias listed on another device, or equal to another device's name. Devices
+// that share a name are variants of one model, such as the a100 entries, and
+// may share aliases.
+func checkAliases(doc any) []Error {
+	var res []Error
+
+	devices := gpuDevices(doc)
+	names := make(map[string][]gpuDevice)
+	aliases := make(map[string][]gpuDevice)
+	for _, d := range devices {
+		if name, ok := d.device["name"].(string); ok {
+			names[name] = append(names[name], d)
+		}
+		for _, alias := range deviceAliases(d) {
+			aliases[alias] = append(aliases[alias], d)
+		}
+	}
+
+	for _, d := range devices {
+		name, _ := d.device["name"].(string)
+		list, _ := d.device["aliases"].([]any)
+
+		for i, a := range list {
+			alias, ok := a.(string)
+			if !ok {
+				continue
+			}
+
+			var msg string
+			if other, ok := otherModel(names[alias], name); ok {
+				msg = fmt.Sprintf("alias %q is the name of %s:%s", alias, other.vendorID, other.deviceID)
+			} else if other, ok := otherModel(aliases[alias], name); ok {
+				msg = fmt.Sprintf("alias %q is also an alias of %s:%s (%s)", alias, other.vendorID, other.deviceID, other.device["name"])
+			} else {
+				continue
+			}
+
+			res = append(res, Error{
+				Pointer: d.pointer() + "/aliases/" + strconv.Itoa(i),
+				Message: msg,
+			})
+		}
+	}
+
+	return res
+}
+
+func deviceAliases(d gpuDevice) []string {
+	list, _ := d.device["aliases"].([]any)
+
+	res := make([]string, 0, len(list))
+	for _, a := range list {
+		if alias, ok := a.(string); ok {
+			res = append(res, alias)
+		}
+	}
+	return res
+}
+
+// otherModel returns the first of devices whose name is not name.
+func otherModel(devices []gpuDevice, name string) (gpuDevice, bool) {
+	for _, d := range devices {
+		if other, _ := d.device["name"].(string); other != name {
+			return d, true
+		}
+	}
+	return gpuDevice{}, false
+}
@@ -0,0 +1,195 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/akash-network/provider-configs/pciids"
+)
+
+func TestCheckVendors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []Error
+	}{
+		{"known", `{"10de":{"name":"nvidia"},"1002":{"name":"amd"}}`, nil},
+		{"unknown ID", `{"ffff":{"name":"acme"}}`, []Error{
+			{Pointer: "/ffff", Message: "unknown PCI vendor ID ffff; add it to pciVendors in validate/vendors.go if it is registered with PCI-SIG"},
+		}},
+		{"name mismatch", `{"10de":{"name":"amd"}}`, []Error{
+			{Pointer: "/10de/name", Message: `PCI vendor 10de is NVIDIA Corporation, want name "nvidia", got "amd"`},
+		}},
+		{"malformed ID left to schema", `{"10DE":{"name":"nvidia"}}`, nil},
+		{"wrong shape left to schema", `{"10de":{"name":1},"1002":[]}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Bytes([]byte(tt.doc), nil, checkVendors)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bytes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPCIVendorsRegistered(t *testing.T) {
+	for id := range pciVendors {
+		if _, ok := pciids.Default().Vendor(id); !ok {
+			t.Errorf("PCI vendor %s is missing from pciids.Default()", id)
+		}
+	}
+}
+
+func TestFileAppliesGPUChecks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pcie", "gpus.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"10de":{"name":"amd","devices":{}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	errs, err := File(path)
+	if err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+
+	want := []Error{{
+		File:    path,
+		Pointer: "/10de/name",
+		Message: `PCI vendor 10de is NVIDIA Corporation, want name "nvidia", got "amd"`,
+	}}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("File() = %v, want %v", errs, want)
+	}
+}
+
+func TestCheckProfileMemory(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles string
+		want     []Error
+	}{
+		{"within device", `{"mig":[{"name":"1g.10gb","memory_size":"10Gi"},{"name":"7g.80gb","memory_size":"80Gi"}],"vgpu":[{"name":"A100D-80C","memory_size":"80Gi"}]}`, nil},
+		{"mig exceeds device", `{"mig":[{"name":"1g.10gb","memory_size":"10Gi"},{"name":"7g.96gb","memory_size":"96Gi"}]}`, []Error{
+			{Pointer: "/10de/devices/20b5/profiles/mig/1/memory_size", Message: "profile memory 96Gi exceeds device memory 80Gi"},
+		}},
+		{"vgpu exceeds device across units", `{"vgpu":[{"name":"A100D-1T","memory_size":"1Ti"}]}`, []Error{
+			{Pointer: "/10de/devices/20b5/profiles/vgpu/0/memory_size", Message: "profile memory 1Ti exceeds device memory 80Gi"},
+		}},
+		{"malformed size left to schema", `{"mig":[{"name":"1g.10gb","memory_size":"96GB"}]}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := `{"10de":{"name":"nvidia","devices":{"20b5":{"name":"a100","memory_size":"80Gi","profiles":` + tt.profiles + `}}}}`
+			got := Bytes([]byte(doc), nil, checkProfileMemory)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bytes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckLifecycle(t *testing.T) {
+	tests := []struct {
+		name string
+		v100 string
+		want []Error
+	}{
+		{"valid", `"deprecated":true,"eol_date":"2024-02-29","superseded_by":"10de:20b0"`, nil},
+		{"superseded by other vendor", `"superseded_by":"1002:738c"`, nil},
+		{"impossible date", `"eol_date":"2023-02-31"`, []Error{
+			{Pointer: "/10de/devices/1db5/eol_date", Message: `"2023-02-31" is not a valid date`},
+		}},
+		{"not a leap year", `"eol_date":"2023-02-29"`, []Error{
+			{Pointer: "/10de/devices/1db5/eol_date", Message: `"2023-02-29" is not a valid date`},
+		}},
+		{"malformed date left to schema", `"eol_date":"31/01/2023"`, nil},
+		{"missing replacement", `"superseded_by":"ffff:ffff"`, []Error{
+			{Pointer: "/10de/devices/1db5/superseded_by", Message: "ffff:ffff is not in the catalog"},
+		}},
+		{"replacement under wrong vendor", `"superseded_by":"1002:20b0"`, []Error{
+			{Pointer: "/10de/devices/1db5/superseded_by", Message: "1002:20b0 is not in the catalog"},
+		}},
+		{"supersedes itself", `"superseded_by":"10de:1db5"`, []Error{
+			{Pointer: "/10de/devices/1db5/superseded_by", Message: "device cannot supersede itself"},
+		}},
+		{"malformed reference left to schema", `"superseded_by":"20b0"`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := `{
+				"10de":{"name":"nvidia","devices":{
+					"1db5":{"name":"v100",` + tt.v100 + `},
+					"20b0":{"name":"a100"}
+				}},
+				"1002":{"name":"amd","devices":{"738c":{"name":"mi100"}}}
+			}`
+			got := Bytes([]byte(doc), nil, checkLifecycle)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bytes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		devices string
+		want    []Error
+	}{
+		{"unique", `
+			"2204":{"name":"rtx3090","aliases":["3090"]},
+			"2684":{"name":"rtx4090","aliases":["4090","ada"]}`, nil},
+		{"shared by same model", `
+			"20b1":{"name":"a100","aliases":["a100pcie"]},
+			"20f1":{"name":"a100","aliases":["a100pcie"]}`, nil},
+		{"same model name as alias", `
+			"20b1":{"name":"a100"},
+			"20b5":{"name":"a100","aliases":["a100"]}`, nil},
+		{"repeated on another model", `
+			"2204":{"name":"rtx3090","aliases":["x"]},
+			"2684":{"name":"rtx4090","aliases":["4090","x"]}`, []Error{
+			{Pointer: "/10de/devices/2204/aliases/0", Message: `alias "x" is also an alias of 10de:2684 (rtx4090)`},
+			{Pointer: "/10de/devices/2684/aliases/1", Message: `alias "x" is also an alias of 10de:2204 (rtx3090)`},
+		}},
+		{"equal to another model's name", `
+			"2204":{"name":"rtx3090"},
+			"2208":{"name":"rtx3080ti","aliases":["rtx3090"]}`, []Error{
+			{Pointer: "/10de/devices/2208/aliases/0", Message: `alias "rtx3090" is the name of 10de:2204`},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := `{"10de":{"name":"nvidia","devices":{` + tt.devices + `}}}`
+			got := Bytes([]byte(doc), nil, checkAliases)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bytes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAliasesAcrossVendors(t *testing.T) {
+	doc := `{
+		"10de":{"name":"nvidia","devices":{"2684":{"name":"rtx4090","aliases":["x"]}}},
+		"1002":{"name":"amd","devices":{"738c":{"name":"mi100","aliases":["x"]}}}
+	}`
+	want := []Error{
+		{Pointer: "/1002/devices/738c/aliases/0", Message: `alias "x" is also an alias of 10de:2684 (rtx4090)`},
+		{Pointer: "/10de/devices/2684/aliases/0", Message: `alias "x" is also an alias of 1002:738c (mi100)`},
+	}
+
+	got := Bytes([]byte(doc), nil, GPUChecks...)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Bytes() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,451 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// keywords lists the JSON Schema keywords a Schema understands. Annotations
+// are accepted and ignored; any other keyword makes ParseSchema fail, so a
+// schema change that this package cannot enforce is noticed rather than
+// silently passing every catalog.
+var keywords = map[string]bool{
+	// annotations
+	"$schema":     true,
+	"$id":         true,
+	"$defs":       true,
+	"title":       true,
+	"description": true,
+	"format":      true,
+	// assertions and applicators
+	"$ref":                 true,
+	"type":                 true,
+	"enum":                 true,
+	"pattern":              true,
+	"minimum":              true,
+	"maximum":              true,
+	"minItems":             true,
+	"uniqueItems":          true,
+	"items":                true,
+	"required":             true,
+	"minProperties":        true,
+	"properties":           true,
+	"additionalProperties": true,
+	"propertyNames":        true,
+}
+
+var types = map[string]bool{
+	"object":  true,
+	"array":   true,
+	"string":  true,
+	"number":  true,
+	"integer": true,
+	"boolean": true,
+	"null":    true,
+}
+
+// Schema is a parsed JSON Schema (draft 2020-12) restricted to the keywords
+// used by the catalog schemas in this repository.
+type Schema struct {
+	root     any
+	patterns map[string]*regexp.Regexp
+}
+
+// LoadSchema reads and parses the schema at path.
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSchema(data)
+}
+
+// ParseSchema parses a schema document, compiling its patterns and resolving
+// its references up front.
+func ParseSchema(data []byte) (*Schema, error) {
+	var root any
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&root); err == io.EOF {
+		return nil, errors.New("invalid JSON: empty document")
+	} else if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON: unexpected data after top-level value")
+	}
+	if dups := duplicateKeys(data); len(dups) != 0 {
+		return nil, fmt.Errorf("%s: %s", dups[0].Pointer, dups[0].Message)
+	}
+
+	s := &Schema{
+		root:     root,
+		patterns: make(map[string]*regexp.Regexp),
+	}
+	if err := s.compile(root, ""); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Schema) compile(node any, ptr string) error {
+	if _, ok := node.(bool); ok {
+		return nil
+	}
+
+	n, ok := node.(map[string]any)
+	if !ok && ptr == "" {
+		return errors.New("schema must be an object or boolean")
+	} else if !ok {
+		return fmt.Errorf("%s: schema must be an object or boolean", ptr)
+	}
+
+	for key, val := range n {
+		child := ptr + "/" + escapePointer(key)
+
+		if !keywords[key] {
+			return fmt.Errorf("%s: unsupported keyword", child)
+		}
+
+		switch key {
+		case "$defs", "properties":
+			defs, ok := val.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s: must be an object", child)
+			}
+			for name, sub := range defs {
+				if err := s.compile(sub, child+"/"+escapePointer(name)); err != nil {
+					return err
+				}
+			}
+		case "additionalProperties", "propertyNames", "items":
+			if err := s.compile(val, child); err != nil {
+				return err
+			}
+		case "$ref":
+			ref, ok := val.(string)
+			if !ok {
+				return fmt.Errorf("%s: must be a string", child)
+			}
+			if _, err := s.resolve(ref); err != nil {
+				return fmt.Errorf("%s: %w", child, err)
+			}
+		case "type":
+			t, ok := val.(string)
+			if !ok || !types[t] {
+				return fmt.Errorf("%s: unsupported type %v", child, val)
+			}
+		case "pattern":
+			p, ok := val.(string)
+			if !ok {
+				return fmt.Errorf("%s: must be a string", child)
+			}
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("%s: %w", child, err)
+			}
+			s.patterns[p] = re
+		case "enum", "required":
+			if _, ok := val.([]any); !ok {
+				return fmt.Errorf("%s: must be an array", child)
+			}
+		case "minimum", "maximum", "minItems", "minProperties":
+			if _, ok := val.(json.Number); !ok {
+				return fmt.Errorf("%s: must be a number", child)
+			}
+		case "uniqueItems":
+			if _, ok := val.(bool); !ok {
+				return fmt.Errorf("%s: must be a boolean", child)
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolve looks up a reference local to the schema document, e.g.
+// "#/$defs/device".
+func (s *Schema) resolve(ref string) (any, error) {
+	ptr, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("unsupported non-local reference %q", ref)
+	}
+
+	node := s.root
+	if ptr == "" {
+		return node, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid reference %q", ref)
+	}
+
+	for _, token := range strings.Split(ptr[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable reference %q", ref)
+		}
+		if node, ok = m[token]; !ok {
+			return nil, fmt.Errorf("unresolvable reference %q", ref)
+		}
+	}
+
+	return node, nil
+}
+
+func (s *Schema) validate(doc any) []Error {
+	var res []Error
+	s.eval(s.root, doc, "", &res)
+	return res
+}
+
+func (s *Schema) eval(node any, v any, ptr string, res *[]Error) {
+	fail := func(ptr, format string, args ...any) {
+		*res = append(*res, Error{Pointer: ptr, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if b, ok := node.(bool); ok {
+		if !b {
+			fail(ptr, "value not allowed")
+		}
+		return
+	}
+	n := node.(map[string]any)
+
+	if ref, ok := n["$ref"].(string); ok {
+		target, _ := s.resolve(ref)
+		s.eval(target, v, ptr, res)
+	}
+
+	if t, ok := n["type"].(string); ok && !hasType(v, t) {
+		fail(ptr, "expected %s, got %s", t, typeOf(v))
+		return
+	}
+
+	if enum, ok := n["enum"].([]any); ok && !contains(enum, v) {
+		fail(ptr, "%s is not one of %s", encode(v), encodeList(enum))
+	}
+
+	switch v := v.(type) {
+	case string:
+		if p, ok := n["pattern"].(string); ok && !s.patterns[p].MatchString(v) {
+			fail(ptr, "%q does not match %s", v, p)
+		}
+	case json.Number:
+		r, _ := rat(v)
+		if minimum, ok := ratOf(n["minimum"]); ok && r.Cmp(minimum) < 0 {
+			fail(ptr, "%s is less than minimum %s", v, n["minimum"])
+		}
+		if maximum, ok := ratOf(n["maximum"]); ok && r.Cmp(maximum) > 0 {
+			fail(ptr, "%s is greater than maximum %s", v, n["maximum"])
+		}
+	case []any:
+		if minItems, ok := ratOf(n["minItems"]); ok && count(len(v)).Cmp(minItems) < 0 {
+			fail(ptr, "expected at least %s items, got %d", n["minItems"], len(v))
+		}
+		if unique, _ := n["uniqueItems"].(bool); unique {
+			seen := make(map[string]int)
+			for i, item := range v {
+				key := equalKey(item)
+				if first, ok := seen[key]; ok {
+					fail(ptr+"/"+strconv.Itoa(i), "duplicate of item %d", first)
+					continue
+				}
+				seen[key] = i
+			}
+		}
+		if items, ok := n["items"]; ok {
+			for i, item := range v {
+				s.eval(items, item, ptr+"/"+strconv.Itoa(i), res)
+			}
+		}
+	case map[string]any:
+		if required, ok := n["required"].([]any); ok {
+			for _, r := range required {
+				name, _ := r.(string)
+				if _, ok := v[name]; !ok {
+					fail(ptr, "missing required field %q", name)
+				}
+			}
+		}
+		if minProperties, ok := ratOf(n["minProperties"]); ok && count(len(v)).Cmp(minProperties) < 0 {
+			fail(ptr, "expected at least %s entries, got %d", n["minProperties"], len(v))
+		}
+
+		props, _ := n["properties"].(map[string]any)
+		additional, hasAdditional := n["additionalProperties"]
+		names, hasNames := n["propertyNames"]
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			child := ptr + "/" + escapePointer(key)
+
+			if hasNames {
+				var keyErrs []Error
+				s.eval(names, key, child, &keyErrs)
+				for _, e := range keyErrs {
+					fail(e.Pointer, "invalid key: %s", e.Message)
+				}
+			}
+
+			if sub, ok := props[key]; ok {
+				s.eval(sub, v[key], child, res)
+			} else if b, ok := additional.(bool); ok && !b {
+				fail(child, "unknown field")
+			} else if hasAdditional {
+				s.eval(additional, v[key], child, res)
+			}
+		}
+	}
+}
+
+func hasType(v any, t string) bool {
+	switch v := v.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case []any:
+		return t == "array"
+	case map[string]any:
+		return t == "object"
+	case json.Number:
+		if t == "integer" {
+			r, ok := rat(v)
+			return ok && r.IsInt()
+		}
+		return t == "number"
+	}
+	return false
+}
+
+func typeOf(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case json.Number:
+		if hasType(v, "integer") {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// rat returns the exact value of a JSON number, so that 16 and 16.0 are the
+// same integer as JSON Schema requires.
+func rat(n json.Number) (*big.Rat, bool) {
+	return new(big.Rat).SetString(n.String())
+}
+
+func ratOf(v any) (*big.Rat, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return nil, false
+	}
+	return rat(n)
+}
+
+func count(n int) *big.Rat {
+	return big.NewRat(int64(n), 1)
+}
+
+func contains(list []any, v any) bool {
+	key := equalKey(v)
+	for _, item := range list {
+		if equalKey(item) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// equalKey renders v so that two values produce the same key exactly when
+// JSON Schema considers them equal: object keys are sorted and numbers are
+// compared by value.
+func equalKey(v any) string {
+	var b strings.Builder
+	writeKey(&b, v)
+	return b.String()
+}
+
+func writeKey(b *strings.Builder, v any) {
+	switch v := v.(type) {
+	case json.Number:
+		if r, ok := rat(v); ok {
+			b.WriteString(r.RatString())
+		} else {
+			b.WriteString(v.String())
+		}
+	case []any:
+		b.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeKey(b, item)
+		}
+		b.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Quote(key))
+			b.WriteByte(':')
+			writeKey(b, v[key])
+		}
+		b.WriteByte('}')
+	default:
+		b.WriteString(encode(v))
+	}
+}
+
+// encode renders v as JSON for error messages.
+func encode(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func encodeList(list []any) string {
+	items := make([]string, len(list))
+	for i, item := range list {
+		items[i] = encode(item)
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,193 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaKeywords(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		doc    string
+		want   []Error
+	}{
+		{"type string", `{"type":"string"}`, `"x"`, nil},
+		{"type string fail", `{"type":"string"}`, `1`, []Error{
+			{Message: "expected string, got integer"},
+		}},
+		{"type integer", `{"type":"integer"}`, `16`, nil},
+		{"type integer with zero fraction", `{"type":"integer"}`, `16.0`, nil},
+		{"type integer with exponent", `{"type":"integer"}`, `1.6e1`, nil},
+		{"type integer fail", `{"type":"integer"}`, `16.5`, []Error{
+			{Message: "expected integer, got number"},
+		}},
+		{"type number accepts integer", `{"type":"number"}`, `16`, nil},
+		{"type number fail", `{"type":"number"}`, `"16"`, []Error{
+			{Message: "expected number, got string"},
+		}},
+		{"type boolean", `{"type":"boolean"}`, `false`, nil},
+		{"type boolean fail", `{"type":"boolean"}`, `0`, []Error{
+			{Message: "expected boolean, got integer"},
+		}},
+		{"type null", `{"type":"null"}`, `null`, nil},
+		{"type null fail", `{"type":"null"}`, `{}`, []Error{
+			{Message: "expected null, got object"},
+		}},
+		{"type array", `{"type":"array"}`, `[]`, nil},
+		{"type object fail", `{"type":"object"}`, `[]`, []Error{
+			{Message: "expected object, got array"},
+		}},
+		{"type mismatch skips other keywords", `{"type":"string","pattern":"^a$"}`, `1`, []Error{
+			{Message: "expected string, got integer"},
+		}},
+
+		{"enum", `{"enum":[1,2,4,8,16]}`, `16`, nil},
+		{"enum compares numbers by value", `{"enum":[1,2,4,8,16]}`, `16.0`, nil},
+		{"enum fail", `{"enum":[1,2,4,8,16]}`, `3`, []Error{
+			{Message: "3 is not one of 1, 2, 4, 8, 16"},
+		}},
+		{"enum strings", `{"enum":["PCIe","SXM"]}`, `"SXM"`, nil},
+		{"enum strings fail", `{"enum":["PCIe","SXM"]}`, `"pcie"`, []Error{
+			{Message: `"pcie" is not one of "PCIe", "SXM"`},
+		}},
+
+		{"pattern", `{"pattern":"^[0-9a-f]{4}$"}`, `"10de"`, nil},
+		{"pattern fail", `{"pattern":"^[0-9a-f]{4}$"}`, `"10DE"`, []Error{
+			{Message: `"10DE" does not match ^[0-9a-f]{4}$`},
+		}},
+		{"pattern ignores non-strings", `{"pattern":"^a$"}`, `1`, nil},
+
+		{"minimum inclusive", `{"minimum":1}`, `1`, nil},
+		{"minimum fail", `{"minimum":1}`, `0.5`, []Error{
+			{Message: "0.5 is less than minimum 1"},
+		}},
+		{"maximum inclusive", `{"maximum":6}`, `6.0`, nil},
+		{"maximum fail", `{"maximum":6}`, `7`, []Error{
+			{Message: "7 is greater than maximum 6"},
+		}},
+
+		{"minItems", `{"minItems":1}`, `[1]`, nil},
+		{"minItems fail", `{"minItems":1}`, `[]`, []Error{
+			{Message: "expected at least 1 items, got 0"},
+		}},
+		{"uniqueItems", `{"uniqueItems":true}`, `[1,"1",[1]]`, nil},
+		{"uniqueItems fail", `{"uniqueItems":true}`, `["a","b","a"]`, []Error{
+			{Pointer: "/2", Message: "duplicate of item 0"},
+		}},
+		{"uniqueItems compares numbers by value", `{"uniqueItems":true}`, `[16,16.0]`, []Error{
+			{Pointer: "/1", Message: "duplicate of item 0"},
+		}},
+		{"uniqueItems compares objects regardless of key order", `{"uniqueItems":true}`, `[{"a":1,"b":2},{"b":2,"a":1.0}]`, []Error{
+			{Pointer: "/1", Message: "duplicate of item 0"},
+		}},
+		{"uniqueItems false", `{"uniqueItems":false}`, `["a","a"]`, nil},
+		{"items", `{"items":{"type":"string"}}`, `["a","b"]`, nil},
+		{"items fail", `{"items":{"type":"string"}}`, `["a",2]`, []Error{
+			{Pointer: "/1", Message: "expected string, got integer"},
+		}},
+
+		{"required", `{"required":["name"]}`, `{"name":"a40"}`, nil},
+		{"required fail", `{"required":["name","devices"]}`, `{}`, []Error{
+			{Message: `missing required field "name"`},
+			{Message: `missing required field "devices"`},
+		}},
+		{"minProperties", `{"minProperties":1}`, `{"a":1}`, nil},
+		{"minProperties fail", `{"minProperties":1}`, `{}`, []Error{
+			{Message: "expected at least 1 entries, got 0"},
+		}},
+		{"properties", `{"properties":{"a":{"type":"string"}}}`, `{"a":"x","b":1}`, nil},
+		{"properties fail", `{"properties":{"a":{"type":"string"}}}`, `{"a":1}`, []Error{
+			{Pointer: "/a", Message: "expected string, got integer"},
+		}},
+		{"additionalProperties false", `{"properties":{"a":{}},"additionalProperties":false}`, `{"a":1,"memroy_size":"1Gi"}`, []Error{
+			{Pointer: "/memroy_size", Message: "unknown field"},
+		}},
+		{"additionalProperties schema", `{"properties":{"a":{}},"additionalProperties":{"type":"integer"}}`, `{"a":"x","b":1,"c":"y"}`, []Error{
+			{Pointer: "/c", Message: "expected integer, got string"},
+		}},
+		{"propertyNames", `{"propertyNames":{"pattern":"^[0-9a-f]{4}$"}}`, `{"10de":1}`, nil},
+		{"propertyNames fail", `{"propertyNames":{"pattern":"^[0-9a-f]{4}$"}}`, `{"10DE":1}`, []Error{
+			{Pointer: "/10DE", Message: `invalid key: "10DE" does not match ^[0-9a-f]{4}$`},
+		}},
+		{"properties reported in key order", `{"additionalProperties":false}`, `{"b":1,"a":2}`, []Error{
+			{Pointer: "/a", Message: "unknown field"},
+			{Pointer: "/b", Message: "unknown field"},
+		}},
+
+		{"true schema", `{"items":true}`, `[1]`, nil},
+		{"false schema", `{"items":false}`, `[1]`, []Error{
+			{Pointer: "/0", Message: "value not allowed"},
+		}},
+		{"annotations ignored", `{"$schema":"x","$id":"y","title":"t","description":"d","format":"date"}`, `"not a date"`, nil},
+
+		{"$ref to $defs", `{"$defs":{"id":{"pattern":"^a"}},"properties":{"x":{"$ref":"#/$defs/id"}}}`, `{"x":"abc"}`, nil},
+		{"$ref to $defs fail", `{"$defs":{"id":{"pattern":"^a"}},"properties":{"x":{"$ref":"#/$defs/id"}}}`, `{"x":"b"}`, []Error{
+			{Pointer: "/x", Message: `"b" does not match ^a`},
+		}},
+		{"$ref with escaped tokens", `{"$defs":{"a/b~c":{"type":"integer"}},"items":{"$ref":"#/$defs/a~1b~0c"}}`, `[1,"x"]`, []Error{
+			{Pointer: "/1", Message: "expected integer, got string"},
+		}},
+		{"$ref to root", `{"required":["id"],"properties":{"child":{"$ref":"#"}}}`, `{"id":1,"child":{"id":2,"child":{}}}`, []Error{
+			{Pointer: "/child/child", Message: `missing required field "id"`},
+		}},
+		{"$ref applies alongside siblings", `{"$defs":{"s":{"type":"string"}},"$ref":"#/$defs/s","pattern":"^a"}`, `"b"`, []Error{
+			{Message: `"b" does not match ^a`},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch, err := ParseSchema([]byte(tt.schema))
+			if err != nil {
+				t.Fatalf("ParseSchema() error = %v", err)
+			}
+
+			got := Bytes([]byte(tt.doc), sch)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bytes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{"empty", ``, "invalid JSON: empty document"},
+		{"syntax", `{"type":}`, "invalid JSON"},
+		{"trailing data", `{}]`, "invalid JSON: unexpected data after top-level value"},
+		{"duplicate key", `{"type":"string","type":"integer"}`, `/type: duplicate key "type"`},
+		{"not a schema", `"string"`, "schema must be an object or boolean"},
+		{"unsupported keyword", `{"oneOf":[]}`, "/oneOf: unsupported keyword"},
+		{"nested unsupported keyword", `{"$defs":{"d":{"properties":{"x":{"anyOf":[]}}}}}`, "/$defs/d/properties/x/anyOf: unsupported keyword"},
+		{"unsupported keyword under items", `{"items":{"const":1}}`, "/items/const: unsupported keyword"},
+		{"type list", `{"type":["string","null"]}`, "/type: unsupported type"},
+		{"unknown type", `{"type":"int"}`, "/type: unsupported type int"},
+		{"non-local ref", `{"$ref":"other.json#/x"}`, `/$ref: unsupported non-local reference "other.json#/x"`},
+		{"unresolvable ref", `{"$ref":"#/$defs/missing"}`, `/$ref: unresolvable reference "#/$defs/missing"`},
+		{"invalid ref", `{"$ref":"#defs"}`, `/$ref: invalid reference "#defs"`},
+		{"bad pattern", `{"pattern":"("}`, "/pattern: error parsing regexp"},
+		{"bad required", `{"required":"name"}`, "/required: must be an array"},
+		{"bad minimum", `{"minimum":"1"}`, "/minimum: must be a number"},
+		{"bad uniqueItems", `{"uniqueItems":1}`, "/uniqueItems: must be a boolean"},
+		{"bad properties", `{"properties":[]}`, "/properties: must be an object"},
+		{"bad subschema", `{"items":1}`, "/items: schema must be an object or boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchema([]byte(tt.schema))
+			if err == nil {
+				t.Fatalf("ParseSchema() error = nil, want %q", tt.want)
+			}
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("ParseSchema() error = %q, want prefix %q", err, tt.want)
+			}
+		})
+	}
+}
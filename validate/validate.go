@@ -0,0 +1,153 @@
+// Package validate checks device catalog files under devices/.
+//
+// A catalog named <name>.json is checked against the JSON Schema stored next
+// to it as <name>.schema.json, if one exists. Every catalog is also checked
+// for duplicate object keys, which JSON parsers silently collapse to the last
+// occurrence and a schema therefore cannot see. Known catalogs additionally
+// get the catalog-wide Checks registered for them, such as GPUChecks for
+// pcie/gpus.json.
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const schemaSuffix = ".schema.json"
+
+// Check is a catalog rule that a JSON Schema cannot express. It is given the
+// decoded document, with numbers as json.Number, and must skip any part that
+// does not have the shape it expects; schema violations are reported by the
+// schema.
+type Check func(doc any) []Error
+
+// catalogChecks maps a catalog's directory and file name to its Checks.
+var catalogChecks = map[string][]Check{
+	"pcie/gpus.json": GPUChecks,
+}
+
+// Error is a single problem found in a catalog file.
+type Error struct {
+	// File is the path of the catalog as given to File or found by Tree.
+	File string
+	// Pointer is the RFC 6901 JSON pointer of the offending value; empty for
+	// the document root.
+	Pointer string
+	Message string
+}
+
+func (e Error) Error() string {
+	msg := e.Message
+	if e.Pointer != "" {
+		msg = e.Pointer + ": " + msg
+	}
+	if e.File != "" {
+		msg = e.File + ": " + msg
+	}
+	return msg
+}
+
+// Tree validates every catalog file under root. Schema files are not
+// validated as catalogs. A file that cannot be checked does not stop the
+// walk; all such failures are joined into the returned error.
+func Tree(root string) ([]Error, error) {
+	var res []Error
+	var failed []error
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			failed = append(failed, err)
+			return nil
+		}
+		if d.IsDir() || !isCatalog(path) {
+			return nil
+		}
+
+		errs, err := File(path)
+		if err != nil {
+			failed = append(failed, err)
+		}
+		res = append(res, errs...)
+
+		return nil
+	})
+	if err != nil {
+		failed = append(failed, err)
+	}
+
+	return res, errors.Join(failed...)
+}
+
+// File validates a single catalog file. The returned error reports problems
+// reading the catalog or its schema; problems with the catalog contents are
+// returned as Errors.
+func File(path string) ([]Error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var sch *Schema
+	schemaPath := strings.TrimSuffix(path, ".json") + schemaSuffix
+	if _, err := os.Stat(schemaPath); err == nil {
+		if sch, err = LoadSchema(schemaPath); err != nil {
+			return nil, fmt.Errorf("%s: %w", schemaPath, err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	var res []Error
+	for _, f := range Bytes(data, sch, catalogChecks[catalogName(path)]...) {
+		f.File = path
+		res = append(res, f)
+	}
+
+	return res, nil
+}
+
+// Bytes validates catalog data against sch and checks. A nil sch and no
+// checks only verify that data is well-formed JSON without duplicate keys.
+// The File field of the returned Errors is left empty.
+func Bytes(data []byte, sch *Schema, checks ...Check) []Error {
+	var doc any
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err == io.EOF {
+		return []Error{{Message: "invalid JSON: empty document"}}
+	} else if err != nil {
+		return []Error{{Message: fmt.Sprintf("invalid JSON: %v", err)}}
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return []Error{{Message: "invalid JSON: unexpected data after top-level value"}}
+	}
+
+	res := duplicateKeys(data)
+	if sch != nil {
+		res = append(res, sch.validate(doc)...)
+	}
+	for _, check := range checks {
+		res = append(res, check(doc)...)
+	}
+
+	return res
+}
+
+func catalogName(path string) string {
+	return filepath.Base(filepath.Dir(path)) + "/" + filepath.Base(path)
+}
+
+func isCatalog(path string) bool {
+	return strings.HasSuffix(path, ".json") && !strings.HasSuffix(path, schemaSuffix)
+}
@@ -0,0 +1,114 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBytesInvalidJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"empty", ``, "invalid JSON: empty document"},
+		{"whitespace", " \n\t", "invalid JSON: empty document"},
+		{"syntax", `{"10de":}`, "invalid JSON: invalid character '}' looking for beginning of value"},
+		{"truncated", `{"10de":{`, "invalid JSON: unexpected EOF"},
+		{"trailing value", `{} {}`, "invalid JSON: unexpected data after top-level value"},
+		{"trailing delimiter", `{"a":1}]`, "invalid JSON: unexpected data after top-level value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := []Error{{Message: tt.want}}
+			if got := Bytes([]byte(tt.doc), nil); !reflect.DeepEqual(got, want) {
+				t.Errorf("Bytes() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestErrorString(t *testing.T) {
+	tests := []struct {
+		err  Error
+		want string
+	}{
+		{Error{File: "gpus.json", Message: "invalid JSON: empty document"}, "gpus.json: invalid JSON: empty document"},
+		{Error{File: "gpus.json", Pointer: "/10de/name", Message: "unknown field"}, "gpus.json: /10de/name: unknown field"},
+		{Error{Pointer: "/10de/name", Message: "unknown field"}, "/10de/name: unknown field"},
+		{Error{Message: "invalid JSON: empty document"}, "invalid JSON: empty document"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestTreeCatalog(t *testing.T) {
+	errs, err := Tree("../devices")
+	if err != nil {
+		t.Fatalf("Tree() error = %v", err)
+	}
+	for _, e := range errs {
+		t.Error(e)
+	}
+}
+
+func TestTree(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, data string) {
+		t.Helper()
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Checked against its schema.
+	write("a/gpus.json", `{"10de":{"name":"NVIDIA"}}`)
+	write("a/gpus.schema.json", `{"additionalProperties":{"properties":{"name":{"pattern":"^[a-z]+$"}}}}`)
+	// No schema, so only duplicate keys are reported.
+	write("b/cpus.json", `{"x":1,"x":2,"y":"anything"}`)
+	// The schema cannot be parsed; the walk carries on past it.
+	write("c/nics.json", `{}`)
+	write("c/nics.schema.json", `{"oneOf":[]}`)
+	// Not catalogs.
+	write("d/README.md", `# notes`)
+
+	errs, err := Tree(root)
+
+	want := []Error{
+		{File: filepath.Join(root, "a/gpus.json"), Pointer: "/10de/name", Message: `"NVIDIA" does not match ^[a-z]+$`},
+		{File: filepath.Join(root, "b/cpus.json"), Pointer: "/x", Message: `duplicate key "x"; only the last occurrence is kept`},
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("Tree() = %v, want %v", errs, want)
+	}
+
+	wantErr := filepath.Join(root, "c/nics.schema.json") + ": /oneOf: unsupported keyword"
+	if err == nil || err.Error() != wantErr {
+		t.Errorf("Tree() error = %v, want %q", err, wantErr)
+	}
+}
+
+func TestTreeMissingRoot(t *testing.T) {
+	_, err := Tree(filepath.Join(t.TempDir(), "missing"))
+	if err == nil || !strings.Contains(err.Error(), "no such file or directory") {
+		t.Errorf("Tree() error = %v, want not-exist error", err)
+	}
+}
+
+func TestFileMissing(t *testing.T) {
+	if _, err := File(filepath.Join(t.TempDir(), "gpus.json")); err == nil {
+		t.Error("File() error = nil, want read error")
+	}
+}
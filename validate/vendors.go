@@ -0,0 +1,11 @@
+package validate
+
+// pciVendors lists the PCI-SIG vendor IDs accepted as top-level keys in
+// gpus.json, with the name the catalog, and so provider GPU attributes, use
+// for each. The registered vendor names come from the pciids package, which
+// must list every ID here.
+var pciVendors = map[string]string{
+	"1002": "amd",
+	"10de": "nvidia",
+	"8086": "intel",
+}